@@ -0,0 +1,163 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// OperatorStageName represents a named stage in the Grafana instance reconciliation process.
+type OperatorStageName string
+
+const (
+	OperatorStageComplete OperatorStageName = "complete"
+)
+
+// OperatorStageStatus represents the outcome of a given OperatorStageName.
+type OperatorStageStatus string
+
+const (
+	OperatorStageResultSuccess OperatorStageStatus = "success"
+	OperatorStageResultFailure OperatorStageStatus = "failure"
+)
+
+// NamespacedResource identifies a dashboard (or other namespaced resource) that has been
+// imported into a Grafana instance, and the UID it was assigned on import.
+type NamespacedResource string
+
+// Namespace returns the namespace portion of the resource.
+func (n NamespacedResource) Namespace() string {
+	namespace, _, _ := n.Split()
+	return namespace
+}
+
+// Name returns the name portion of the resource.
+func (n NamespacedResource) Name() string {
+	_, name, _ := n.Split()
+	return name
+}
+
+// Split breaks the resource down into its namespace, name and Grafana UID.
+func (n NamespacedResource) Split() (namespace string, name string, uid string) {
+	parts := strings.SplitN(string(n), "/", 3)
+	if len(parts) != 3 {
+		return "", "", ""
+	}
+	return parts[0], parts[1], parts[2]
+}
+
+func newNamespacedResource(namespace, name, uid string) NamespacedResource {
+	return NamespacedResource(fmt.Sprintf("%v/%v/%v", namespace, name, uid))
+}
+
+// NamespacedResourceList tracks the dashboards known to a Grafana instance, partitioned by
+// namespace internally so Find/Add/Remove only ever scan the namespace they're asked about
+// instead of the whole fleet. It still (un)marshals as a flat JSON array, so it reads and
+// writes the same wire format as the pre-partitioning flat []NamespacedResource - a Grafana CR
+// written by an older operator version still loads straight into the new representation.
+type NamespacedResourceList struct {
+	byNamespace map[string][]NamespacedResource
+}
+
+func (l NamespacedResourceList) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.All())
+}
+
+func (l *NamespacedResourceList) UnmarshalJSON(data []byte) error {
+	var flat []NamespacedResource
+	if err := json.Unmarshal(data, &flat); err != nil {
+		return err
+	}
+
+	byNamespace := map[string][]NamespacedResource{}
+	for _, resource := range flat {
+		namespace := resource.Namespace()
+		byNamespace[namespace] = append(byNamespace[namespace], resource)
+	}
+	l.byNamespace = byNamespace
+	return nil
+}
+
+// All flattens the list back into a single slice, e.g. for callers that need to range over
+// every dashboard regardless of namespace.
+func (l NamespacedResourceList) All() []NamespacedResource {
+	var flat []NamespacedResource
+	for _, bucket := range l.byNamespace {
+		flat = append(flat, bucket...)
+	}
+	return flat
+}
+
+// DashboardsByNamespace returns the namespace -> dashboards partition directly, for callers
+// (like the dashboard sync loop) that already process dashboards namespace by namespace and
+// would otherwise re-derive this grouping themselves.
+func (l NamespacedResourceList) DashboardsByNamespace() map[string][]NamespacedResource {
+	return l.byNamespace
+}
+
+// Find reports whether namespace/name is present and, if so, returns the Grafana UID
+// it was last imported under.
+func (l NamespacedResourceList) Find(namespace string, name string) (bool, *string) {
+	for _, resource := range l.byNamespace[namespace] {
+		if resource.Name() == name {
+			_, _, uid := resource.Split()
+			return true, &uid
+		}
+	}
+	return false, nil
+}
+
+// Add appends (or replaces) the entry for namespace/name with the given Grafana UID.
+func (l NamespacedResourceList) Add(namespace string, name string, uid string) NamespacedResourceList {
+	updated := l.Remove(namespace, name)
+	byNamespace := updated.copyBuckets()
+	byNamespace[namespace] = append(byNamespace[namespace], newNamespacedResource(namespace, name, uid))
+	return NamespacedResourceList{byNamespace: byNamespace}
+}
+
+// Remove drops the entry for namespace/name, if present.
+func (l NamespacedResourceList) Remove(namespace string, name string) NamespacedResourceList {
+	byNamespace := l.copyBuckets()
+
+	var kept []NamespacedResource
+	for _, resource := range byNamespace[namespace] {
+		if resource.Name() == name {
+			continue
+		}
+		kept = append(kept, resource)
+	}
+
+	if len(kept) == 0 {
+		delete(byNamespace, namespace)
+	} else {
+		byNamespace[namespace] = kept
+	}
+
+	return NamespacedResourceList{byNamespace: byNamespace}
+}
+
+// copyBuckets shallow-copies the namespace map so Add/Remove never mutate the receiver's
+// buckets in place.
+func (l NamespacedResourceList) copyBuckets() map[string][]NamespacedResource {
+	byNamespace := make(map[string][]NamespacedResource, len(l.byNamespace))
+	for namespace, bucket := range l.byNamespace {
+		byNamespace[namespace] = bucket
+	}
+	return byNamespace
+}
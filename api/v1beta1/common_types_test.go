@@ -0,0 +1,190 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestNamespacedResourceListAddFindRemove(t *testing.T) {
+	var list NamespacedResourceList
+
+	list = list.Add("ns-a", "dash-1", "uid-1")
+	list = list.Add("ns-a", "dash-2", "uid-2")
+	list = list.Add("ns-b", "dash-1", "uid-3")
+
+	if found, uid := list.Find("ns-a", "dash-1"); !found || *uid != "uid-1" {
+		t.Fatalf("expected ns-a/dash-1 to resolve to uid-1, got found=%v uid=%v", found, uid)
+	}
+	if found, uid := list.Find("ns-b", "dash-1"); !found || *uid != "uid-3" {
+		t.Fatalf("expected ns-b/dash-1 to resolve to uid-3, got found=%v uid=%v", found, uid)
+	}
+	if found, _ := list.Find("ns-a", "dash-3"); found {
+		t.Fatalf("expected ns-a/dash-3 to be absent")
+	}
+	// a namespace that was never added must not be confused with one that was.
+	if found, _ := list.Find("ns-c", "dash-1"); found {
+		t.Fatalf("expected unknown namespace to be absent")
+	}
+
+	list = list.Remove("ns-a", "dash-1")
+	if found, _ := list.Find("ns-a", "dash-1"); found {
+		t.Fatalf("expected ns-a/dash-1 to be removed")
+	}
+	if found, uid := list.Find("ns-a", "dash-2"); !found || *uid != "uid-2" {
+		t.Fatalf("expected ns-a/dash-2 to be unaffected by removing dash-1, got found=%v uid=%v", found, uid)
+	}
+
+	if len(list.All()) != 2 {
+		t.Fatalf("expected 2 entries remaining, got %v", len(list.All()))
+	}
+}
+
+func TestNamespacedResourceListAddDoesNotMutateReceiver(t *testing.T) {
+	var original NamespacedResourceList
+	original = original.Add("ns-a", "dash-1", "uid-1")
+
+	updated := original.Add("ns-a", "dash-2", "uid-2")
+
+	if len(original.All()) != 1 {
+		t.Fatalf("expected the original list to be unaffected by Add, got %v entries", len(original.All()))
+	}
+	if len(updated.All()) != 2 {
+		t.Fatalf("expected the updated list to contain both entries, got %v", len(updated.All()))
+	}
+}
+
+func TestNamespacedResourceListRemoveDropsEmptyNamespaceBucket(t *testing.T) {
+	var list NamespacedResourceList
+	list = list.Add("ns-a", "dash-1", "uid-1")
+	list = list.Remove("ns-a", "dash-1")
+
+	byNamespace := list.DashboardsByNamespace()
+	if _, ok := byNamespace["ns-a"]; ok {
+		t.Fatalf("expected the ns-a bucket to be dropped once empty, got %v", byNamespace["ns-a"])
+	}
+}
+
+func TestNamespacedResourceListJSONRoundTripsAsFlatArray(t *testing.T) {
+	var list NamespacedResourceList
+	list = list.Add("ns-a", "dash-1", "uid-1")
+	list = list.Add("ns-b", "dash-2", "uid-2")
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+
+	// the wire format must stay a flat array, matching a pre-partitioning operator version.
+	var flat []NamespacedResource
+	if err := json.Unmarshal(data, &flat); err != nil {
+		t.Fatalf("expected the marshaled form to unmarshal as a flat array, got error: %v", err)
+	}
+	if len(flat) != 2 {
+		t.Fatalf("expected 2 entries in the flat array, got %v", len(flat))
+	}
+
+	var roundTripped NamespacedResourceList
+	if err := json.Unmarshal(data, &roundTripped); err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	if found, uid := roundTripped.Find("ns-a", "dash-1"); !found || *uid != "uid-1" {
+		t.Fatalf("expected round-tripped list to resolve ns-a/dash-1, got found=%v uid=%v", found, uid)
+	}
+}
+
+func TestNamespacedResourceListUnmarshalFromLegacyFlatArray(t *testing.T) {
+	// exactly the shape a Grafana CR written by a pre-partitioning operator version would have.
+	legacy := []byte(`["ns-a/dash-1/uid-1","ns-b/dash-2/uid-2"]`)
+
+	var list NamespacedResourceList
+	if err := json.Unmarshal(legacy, &list); err != nil {
+		t.Fatalf("unexpected error unmarshaling legacy flat array: %v", err)
+	}
+
+	if found, uid := list.Find("ns-a", "dash-1"); !found || *uid != "uid-1" {
+		t.Fatalf("expected ns-a/dash-1 to resolve to uid-1, got found=%v uid=%v", found, uid)
+	}
+	if found, uid := list.Find("ns-b", "dash-2"); !found || *uid != "uid-2" {
+		t.Fatalf("expected ns-b/dash-2 to resolve to uid-2, got found=%v uid=%v", found, uid)
+	}
+}
+
+// benchmarkList builds a NamespacedResourceList approximating a fleet of namespaceCount
+// namespaces each running dashboardsPerNamespace dashboards. It takes no instance count: the
+// partitioning itself doesn't vary with the number of Grafana instances, only with the total
+// dashboard count, so the benchmarks below name the instance scale they model instead.
+func benchmarkList(namespaceCount, dashboardsPerNamespace int) NamespacedResourceList {
+	var list NamespacedResourceList
+	for n := 0; n < namespaceCount; n++ {
+		namespace := fmt.Sprintf("ns-%d", n)
+		for d := 0; d < dashboardsPerNamespace; d++ {
+			list = list.Add(namespace, fmt.Sprintf("dash-%d", d), fmt.Sprintf("uid-%d-%d", n, d))
+		}
+	}
+	return list
+}
+
+// BenchmarkNamespacedResourceListFind_10kDashboards20Instances models a fleet-wide 10k
+// dashboards spread across 20 namespaces (one per matched Grafana instance's worth of
+// dashboards, roughly 500 each) and measures a single Find - the partitioning should make this
+// independent of the total dashboard count, only scaling with the size of one namespace bucket.
+func BenchmarkNamespacedResourceListFind_10kDashboards20Instances(b *testing.B) {
+	const namespaces = 20
+	const dashboardsPerNamespace = 10000 / namespaces
+	list := benchmarkList(namespaces, dashboardsPerNamespace)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		list.Find("ns-10", "dash-100")
+	}
+}
+
+// BenchmarkNamespacedResourceListAdd_10kDashboards20Instances measures the cost of adding a
+// dashboard to a namespace bucket in an already-large (10k dashboard, 20 namespace) list.
+func BenchmarkNamespacedResourceListAdd_10kDashboards20Instances(b *testing.B) {
+	const namespaces = 20
+	const dashboardsPerNamespace = 10000 / namespaces
+	list := benchmarkList(namespaces, dashboardsPerNamespace)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		list.Add("ns-10", "new-dash", "new-uid")
+	}
+}
+
+// BenchmarkNamespacedResourceListJSONRoundTrip_10kDashboards20Instances measures the
+// marshal/unmarshal cost of the full flat-array wire format at fleet scale.
+func BenchmarkNamespacedResourceListJSONRoundTrip_10kDashboards20Instances(b *testing.B) {
+	const namespaces = 20
+	const dashboardsPerNamespace = 10000 / namespaces
+	list := benchmarkList(namespaces, dashboardsPerNamespace)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, err := json.Marshal(list)
+		if err != nil {
+			b.Fatalf("unexpected error marshaling: %v", err)
+		}
+		var roundTripped NamespacedResourceList
+		if err := json.Unmarshal(data, &roundTripped); err != nil {
+			b.Fatalf("unexpected error unmarshaling: %v", err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaSpec defines the desired state of a Grafana instance.
+type GrafanaSpec struct {
+	// Config holds the ini-style configuration passed to the Grafana instance.
+	// +optional
+	Config map[string]map[string]string `json:"config,omitempty"`
+
+	// DashboardContentCacheDuration sets a cluster-wide default for how long a fetched
+	// dashboard source (e.g. a url) is cached before being re-fetched. Individual
+	// GrafanaDashboards can override this via spec.contentCacheDuration.
+	// +optional
+	DashboardContentCacheDuration *metav1.Duration `json:"dashboardContentCacheDuration,omitempty"`
+
+	// Client configures how the operator talks to this Grafana instance's HTTP API.
+	// +optional
+	Client *GrafanaClientSpec `json:"client,omitempty"`
+}
+
+// GrafanaClientSpec configures the HTTP client used to reach a Grafana instance's API.
+type GrafanaClientSpec struct {
+	// TLS configures certificate verification and client certificates for this instance.
+	// +optional
+	TLS *GrafanaClientTLS `json:"tls,omitempty"`
+
+	// Timeout bounds every request made to this instance's API. Defaults to 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// ProxyURL routes requests to this instance through an HTTP(S) proxy, for instances only
+	// reachable from behind a corporate network boundary.
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+}
+
+// GrafanaClientTLS configures certificate verification for a Grafana instance's API.
+type GrafanaClientTLS struct {
+	// SecretName references a Secret in the Grafana CR's namespace holding an optional
+	// "ca.crt" (to verify the server's certificate) and an optional "tls.crt"/"tls.key"
+	// pair (to present a client certificate for mTLS).
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// InsecureSkipVerify disables server certificate verification entirely. Defaults to
+	// false; only set this for instances you cannot otherwise obtain a CA bundle for.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// GrafanaStatus defines the observed state of a Grafana instance.
+type GrafanaStatus struct {
+	Stage       OperatorStageName      `json:"stage,omitempty"`
+	StageStatus OperatorStageStatus    `json:"stageStatus,omitempty"`
+	AdminUrl    string                 `json:"adminUrl,omitempty"`
+	Dashboards  NamespacedResourceList `json:"dashboards,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Grafana is the Schema for the grafanas API.
+type Grafana struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaSpec   `json:"spec,omitempty"`
+	Status GrafanaStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GrafanaList contains a list of Grafana.
+type GrafanaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Grafana `json:"items"`
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GitRepositorySpec describes the git repository a GrafanaBackup reads from and writes to.
+type GitRepositorySpec struct {
+	// URL is the clone url, either ssh (git@host:org/repo.git) or https.
+	URL string `json:"url"`
+
+	// Ref is the branch to commit to and restore from. Defaults to "main".
+	// +optional
+	Ref string `json:"ref,omitempty"`
+
+	// SecretName references a Secret in the GrafanaBackup's namespace holding either an ssh
+	// private key ("sshPrivateKey"), an HTTPS token ("token", sent with the username "token")
+	// or an HTTPS "username" and "password" pair. A "knownHosts" key is not read.
+	SecretName string `json:"secretName"`
+
+	// CommitterName and CommitterEmail are used as the git commit author/committer.
+	// +optional
+	CommitterName string `json:"committerName,omitempty"`
+	// +optional
+	CommitterEmail string `json:"committerEmail,omitempty"`
+
+	// CommitMessageTemplate is used as the commit message, defaults to
+	// "backup: {count} dashboard(s) from {grafana}".
+	// +optional
+	CommitMessageTemplate string `json:"commitMessageTemplate,omitempty"`
+}
+
+// GrafanaBackupSpec defines the desired state of a GrafanaBackup.
+type GrafanaBackupSpec struct {
+	// InstanceSelector matches the Grafana instance(s) to back up.
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector"`
+
+	Repository GitRepositorySpec `json:"repository"`
+
+	// Schedule is a standard 5-field cron expression controlling how often a backup is taken.
+	Schedule string `json:"schedule"`
+}
+
+// GrafanaBackupStatus defines the observed state of a GrafanaBackup.
+type GrafanaBackupStatus struct {
+	// LastBackupTime is when a backup was last taken, regardless of whether it produced a commit.
+	// +optional
+	LastBackupTime metav1.Time `json:"lastBackupTime,omitempty"`
+
+	// LastBackupCommit is the SHA of the last commit pushed by a backup, empty if the last
+	// backup run found nothing changed.
+	// +optional
+	LastBackupCommit string `json:"lastBackupCommit,omitempty"`
+
+	// LastRestoreTime is when a restore was last performed from this backup's repository.
+	// +optional
+	LastRestoreTime metav1.Time `json:"lastRestoreTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// GrafanaBackup is the Schema for the grafanabackups API.
+type GrafanaBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaBackupSpec   `json:"spec,omitempty"`
+	Status GrafanaBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GrafanaBackupList contains a list of GrafanaBackup.
+type GrafanaBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaBackup `json:"items"`
+}
+
+// GetRef returns the configured git ref to commit to and restore from, defaulting to "main".
+func (r *GitRepositorySpec) GetRef() string {
+	if r.Ref != "" {
+		return r.Ref
+	}
+	return "main"
+}
+
+// CommitMessage renders the configured commit message template, substituting "{count}" and
+// "{grafana}" with dashboardCount and grafanaName, and falling back to a sensible default
+// template when none is set.
+func (r *GitRepositorySpec) CommitMessage(grafanaName string, dashboardCount int) string {
+	template := r.CommitMessageTemplate
+	if template == "" {
+		template = "backup: {count} dashboard(s) from {grafana}"
+	}
+
+	replacer := strings.NewReplacer(
+		"{count}", fmt.Sprintf("%v", dashboardCount),
+		"{grafana}", grafanaName,
+	)
+	return replacer.Replace(template)
+}
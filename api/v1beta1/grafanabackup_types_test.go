@@ -0,0 +1,57 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+func TestCommitMessageDefaultTemplate(t *testing.T) {
+	repo := &GitRepositorySpec{}
+	got := repo.CommitMessage("my-grafana", 3)
+	want := "backup: 3 dashboard(s) from my-grafana"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestCommitMessageSubstitutesCustomTemplate(t *testing.T) {
+	repo := &GitRepositorySpec{CommitMessageTemplate: "nightly backup: {count} dashboards from {grafana}"}
+	got := repo.CommitMessage("prod", 42)
+	want := "nightly backup: 42 dashboards from prod"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestCommitMessageTemplateWithoutPlaceholdersIsUnchanged(t *testing.T) {
+	repo := &GitRepositorySpec{CommitMessageTemplate: "scheduled dashboard backup"}
+	got := repo.CommitMessage("prod", 1)
+	if got != "scheduled dashboard backup" {
+		t.Fatalf("expected the literal template back, got %q", got)
+	}
+}
+
+func TestGetRefDefaultsToMain(t *testing.T) {
+	repo := &GitRepositorySpec{}
+	if got := repo.GetRef(); got != "main" {
+		t.Fatalf("expected default ref main, got %q", got)
+	}
+
+	repo = &GitRepositorySpec{Ref: "releases"}
+	if got := repo.GetRef(); got != "releases" {
+		t.Fatalf("expected configured ref releases, got %q", got)
+	}
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DashboardSourceType identifies where a dashboard's JSON definition comes from.
+type DashboardSourceType string
+
+const (
+	DashboardSourceTypeRawJson    DashboardSourceType = "json"
+	DashboardSourceTypeUrl        DashboardSourceType = "url"
+	DashboardSourceTypeConfigMap  DashboardSourceType = "configMap"
+	DashboardSourceTypeJsonnet    DashboardSourceType = "jsonnet"
+	DashboardSourceTypeGrafanaCom DashboardSourceType = "grafanaCom"
+)
+
+// sourceTypePriority is the fixed, documented order in which populated sources are tried: a
+// cheap inline definition first, then the sources that require network or API calls, falling
+// back from the most specific/pinned (grafana.com) to the most generic (jsonnet).
+var sourceTypePriority = []DashboardSourceType{
+	DashboardSourceTypeRawJson,
+	DashboardSourceTypeUrl,
+	DashboardSourceTypeGrafanaCom,
+	DashboardSourceTypeConfigMap,
+	DashboardSourceTypeJsonnet,
+}
+
+const (
+	defaultResyncPeriod  = 5 * time.Minute
+	defaultMaxRetries    = int32(10)
+	defaultRetryCooldown = 30 * time.Minute
+)
+
+// GrafanaPlugin is a single Grafana plugin dependency requested by a dashboard.
+type GrafanaPlugin struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// PluginList is a set of Grafana plugin dependencies.
+type PluginList []GrafanaPlugin
+
+// ConfigMapRef points at a key within a ConfigMap in the dashboard's own namespace holding
+// the dashboard JSON.
+type ConfigMapRef struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+// GrafanaComSource identifies a dashboard published on grafana.com/dashboards.
+type GrafanaComSource struct {
+	Id int `json:"id"`
+
+	// Revision pins a specific revision of the dashboard. If unset, the latest revision is used.
+	// +optional
+	Revision int `json:"revision,omitempty"`
+}
+
+// GrafanaDashboardSpec defines the desired state of a GrafanaDashboard.
+type GrafanaDashboardSpec struct {
+	// Json is the dashboard definition, embedded directly in the CR.
+	// +optional
+	Json string `json:"json,omitempty"`
+
+	// Url points at a remote location serving the dashboard JSON.
+	// +optional
+	Url string `json:"url,omitempty"`
+
+	// ConfigMapRef sources the dashboard JSON from a key in a ConfigMap in the same namespace.
+	// +optional
+	ConfigMapRef *ConfigMapRef `json:"configMapRef,omitempty"`
+
+	// Jsonnet is evaluated to produce the dashboard JSON.
+	// +optional
+	Jsonnet string `json:"jsonnet,omitempty"`
+
+	// GrafanaCom sources the dashboard JSON from a dashboard published on grafana.com.
+	// +optional
+	GrafanaCom *GrafanaComSource `json:"grafanaCom,omitempty"`
+
+	// ContentCacheDuration overrides the cluster-wide default (Grafana.Spec.DashboardContentCacheDuration)
+	// for how long a fetched source is cached before being re-fetched.
+	// +optional
+	ContentCacheDuration *metav1.Duration `json:"contentCacheDuration,omitempty"`
+
+	FolderTitle      string                `json:"folderTitle,omitempty"`
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+	Plugins          PluginList            `json:"plugins,omitempty"`
+
+	// MaxRetries caps how many times a failing fetch is retried (with exponential backoff)
+	// before the dashboard is left alone until its spec changes or RetryCooldown elapses.
+	// Defaults to 10.
+	// +optional
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+
+	// RetryCooldown is how long to wait, once MaxRetries has been exhausted, before giving
+	// the source another chance even though the spec hasn't changed. Defaults to 30m.
+	// +optional
+	RetryCooldown *metav1.Duration `json:"retryCooldown,omitempty"`
+}
+
+// GrafanaDashboardStatus defines the observed state of a GrafanaDashboard.
+type GrafanaDashboardStatus struct {
+	// Hash is the checksum of the last successfully applied Spec.Json and Spec.FolderTitle.
+	Hash string `json:"hash,omitempty"`
+
+	// ContentCache holds the last-fetched content of a remote-sourced dashboard (e.g. url),
+	// so unchanged reconciles don't have to re-fetch it every time.
+	// +optional
+	ContentCache *DashboardContentCacheStatus `json:"contentCache,omitempty"`
+
+	// Error records the last fetch failure, if any, and how many times it has been retried.
+	// +optional
+	Error *GrafanaDashboardErrorStatus `json:"error,omitempty"`
+
+	// ResolvedSource is the source type that supplied Spec.Json on the last successful fetch,
+	// e.g. when multiple sources are configured as fallbacks for one another.
+	// +optional
+	ResolvedSource DashboardSourceType `json:"resolvedSource,omitempty"`
+}
+
+// GrafanaDashboardErrorStatus tracks a failing dashboard source so retries can be backed off
+// and eventually paused instead of hammering a broken source forever.
+type GrafanaDashboardErrorStatus struct {
+	Message string `json:"message,omitempty"`
+	Code    int    `json:"code,omitempty"`
+
+	// Retries is the number of consecutive failed attempts since SpecFingerprint last changed.
+	Retries int32 `json:"retries,omitempty"`
+
+	// LastAttempt is when the fetch that produced Message/Code was made.
+	LastAttempt metav1.Time `json:"lastAttempt,omitempty"`
+
+	// SpecFingerprint is the dashboard's source fingerprint as of LastAttempt, used to tell a
+	// genuine spec change apart from a retry of the same broken source.
+	SpecFingerprint string `json:"specFingerprint,omitempty"`
+}
+
+// DashboardContentCacheStatus is the last-known fetch result for a remote dashboard source.
+type DashboardContentCacheStatus struct {
+	// SourceUrl is the url the cached content was fetched from. The cache is invalidated
+	// whenever this no longer matches the dashboard's current source.
+	SourceUrl string `json:"sourceUrl,omitempty"`
+
+	// FetchedAt is when ContentGzip was last populated from the source or last revalidated
+	// against it (a 304 Not Modified response refreshes it too).
+	FetchedAt metav1.Time `json:"fetchedAt,omitempty"`
+
+	// ETag and LastModified are the validators returned by the source, used to make
+	// conditional requests once the cache has expired.
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+
+	// ContentGzip is the gzip-compressed dashboard JSON as last fetched from SourceUrl.
+	ContentGzip []byte `json:"contentGzip,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// GrafanaDashboard is the Schema for the grafanadashboards API.
+type GrafanaDashboard struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaDashboardSpec   `json:"spec,omitempty"`
+	Status GrafanaDashboardStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GrafanaDashboardList contains a list of GrafanaDashboard.
+type GrafanaDashboardList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaDashboard `json:"items"`
+}
+
+// Find returns the dashboard matching namespace/name, or nil if it isn't present in the list.
+func (l *GrafanaDashboardList) Find(namespace string, name string) *GrafanaDashboard {
+	for i := range l.Items {
+		if l.Items[i].Namespace == namespace && l.Items[i].Name == name {
+			return &l.Items[i]
+		}
+	}
+	return nil
+}
+
+// GetSourceTypes returns the source types that are populated on this dashboard's spec, in the
+// fixed priority order they are tried in: DashboardSourceTypeRawJson, then Url, GrafanaCom,
+// ConfigMap and finally Jsonnet. A dashboard may configure more than one, e.g. a GrafanaCom
+// source pinned as primary with a ConfigMapRef as an offline fallback.
+func (d *GrafanaDashboard) GetSourceTypes() []DashboardSourceType {
+	populated := map[DashboardSourceType]bool{
+		DashboardSourceTypeRawJson:    d.Spec.Json != "",
+		DashboardSourceTypeUrl:        d.Spec.Url != "",
+		DashboardSourceTypeGrafanaCom: d.Spec.GrafanaCom != nil,
+		DashboardSourceTypeConfigMap:  d.Spec.ConfigMapRef != nil,
+		DashboardSourceTypeJsonnet:    d.Spec.Jsonnet != "",
+	}
+
+	var sourceTypes []DashboardSourceType
+	for _, sourceType := range sourceTypePriority {
+		if populated[sourceType] {
+			sourceTypes = append(sourceTypes, sourceType)
+		}
+	}
+
+	return sourceTypes
+}
+
+// GetResyncPeriod returns the interval after which a successfully reconciled dashboard
+// should be re-checked against its source.
+func (d *GrafanaDashboard) GetResyncPeriod() time.Duration {
+	return defaultResyncPeriod
+}
+
+// Hash returns a checksum of the fields that determine whether the dashboard needs to be
+// re-imported into Grafana.
+func (d *GrafanaDashboard) Hash() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%v/%v", d.Spec.Json, d.Spec.FolderTitle)))
+	return fmt.Sprintf("%x", sum)
+}
+
+// Unchanged reports whether the dashboard's last-applied hash still matches its current spec.
+func (d *GrafanaDashboard) Unchanged() bool {
+	return d.Status.Hash == d.Hash()
+}
+
+// SpecFingerprint hashes the parts of the spec that determine which source is being fetched,
+// so a genuine spec change can be told apart from a retry of the same failing source.
+func (d *GrafanaDashboard) SpecFingerprint() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%v/%v/%v/%v/%v/%v",
+		d.Spec.Json, d.Spec.Url, d.Spec.ConfigMapRef, d.Spec.Jsonnet, d.Spec.GrafanaCom, d.Spec.FolderTitle)))
+	return fmt.Sprintf("%x", sum)
+}
+
+// MaxRetries returns the dashboard's configured retry cap, defaulting to 10.
+func (d *GrafanaDashboard) MaxRetries() int32 {
+	if d.Spec.MaxRetries != nil {
+		return *d.Spec.MaxRetries
+	}
+	return defaultMaxRetries
+}
+
+// RetryCooldown returns how long to wait, once MaxRetries is exhausted, before giving the
+// source another chance. Defaults to 30m.
+func (d *GrafanaDashboard) RetryCooldown() time.Duration {
+	if d.Spec.RetryCooldown != nil {
+		return d.Spec.RetryCooldown.Duration
+	}
+	return defaultRetryCooldown
+}
+
+// ShouldRetry reports whether a failing fetch should be attempted again right now: always
+// true for a spec that changed since the last attempt (that resets the retry count), true
+// while under MaxRetries, and true again once RetryCooldown has elapsed since the last attempt.
+func (d *GrafanaDashboard) ShouldRetry() bool {
+	errStatus := d.Status.Error
+	if errStatus == nil {
+		return true
+	}
+	if errStatus.SpecFingerprint != d.SpecFingerprint() {
+		return true
+	}
+	if errStatus.Retries < d.MaxRetries() {
+		return true
+	}
+	return time.Since(errStatus.LastAttempt.Time) >= d.RetryCooldown()
+}
+
+// EffectiveContentCacheDuration returns how long a fetched source may be served from the
+// cache before being re-fetched, preferring the dashboard's own override over the
+// cluster-wide default configured on the matched Grafana instance.
+func (d *GrafanaDashboard) EffectiveContentCacheDuration(grafanaDefault *metav1.Duration) time.Duration {
+	if d.Spec.ContentCacheDuration != nil {
+		return d.Spec.ContentCacheDuration.Duration
+	}
+	if grafanaDefault != nil {
+		return grafanaDefault.Duration
+	}
+	return 0
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetSourceTypesOrdersByFixedPriority(t *testing.T) {
+	dashboard := &GrafanaDashboard{
+		Spec: GrafanaDashboardSpec{
+			ConfigMapRef: &ConfigMapRef{Name: "cm", Key: "key"},
+			GrafanaCom:   &GrafanaComSource{Id: 1},
+		},
+	}
+
+	got := dashboard.GetSourceTypes()
+	want := []DashboardSourceType{DashboardSourceTypeGrafanaCom, DashboardSourceTypeConfigMap}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestGetSourceTypesEmptyWhenNoSourceConfigured(t *testing.T) {
+	dashboard := &GrafanaDashboard{}
+	if got := dashboard.GetSourceTypes(); len(got) != 0 {
+		t.Fatalf("expected no source types, got %v", got)
+	}
+}
+
+func TestShouldRetryResetsOnSpecChange(t *testing.T) {
+	dashboard := &GrafanaDashboard{
+		Spec: GrafanaDashboardSpec{Url: "http://example.com/dashboard.json"},
+		Status: GrafanaDashboardStatus{
+			Error: &GrafanaDashboardErrorStatus{
+				Retries:         10,
+				LastAttempt:     metav1.Now(),
+				SpecFingerprint: "stale-fingerprint",
+			},
+		},
+	}
+
+	if !dashboard.ShouldRetry() {
+		t.Fatalf("expected ShouldRetry to be true once the spec fingerprint no longer matches the recorded error")
+	}
+}
+
+func TestShouldRetryFalseOnceMaxRetriesExhaustedAndWithinCooldown(t *testing.T) {
+	dashboard := &GrafanaDashboard{
+		Spec: GrafanaDashboardSpec{Url: "http://example.com/dashboard.json"},
+	}
+	dashboard.Status.Error = &GrafanaDashboardErrorStatus{
+		Retries:         dashboard.MaxRetries(),
+		LastAttempt:     metav1.Now(),
+		SpecFingerprint: dashboard.SpecFingerprint(),
+	}
+
+	if dashboard.ShouldRetry() {
+		t.Fatalf("expected ShouldRetry to be false immediately after exhausting MaxRetries")
+	}
+}
+
+func TestShouldRetryTrueAfterCooldownElapses(t *testing.T) {
+	dashboard := &GrafanaDashboard{
+		Spec: GrafanaDashboardSpec{
+			Url:           "http://example.com/dashboard.json",
+			RetryCooldown: &metav1.Duration{Duration: time.Minute},
+		},
+	}
+	dashboard.Status.Error = &GrafanaDashboardErrorStatus{
+		Retries:         dashboard.MaxRetries(),
+		LastAttempt:     metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+		SpecFingerprint: dashboard.SpecFingerprint(),
+	}
+
+	if !dashboard.ShouldRetry() {
+		t.Fatalf("expected ShouldRetry to be true once RetryCooldown has elapsed")
+	}
+}
+
+func TestMaxRetriesAndRetryCooldownDefaults(t *testing.T) {
+	dashboard := &GrafanaDashboard{}
+	if got := dashboard.MaxRetries(); got != defaultMaxRetries {
+		t.Fatalf("expected default max retries %v, got %v", defaultMaxRetries, got)
+	}
+	if got := dashboard.RetryCooldown(); got != defaultRetryCooldown {
+		t.Fatalf("expected default retry cooldown %v, got %v", defaultRetryCooldown, got)
+	}
+}
+
+func TestEffectiveContentCacheDurationPrefersDashboardOverride(t *testing.T) {
+	dashboard := &GrafanaDashboard{
+		Spec: GrafanaDashboardSpec{ContentCacheDuration: &metav1.Duration{Duration: time.Minute}},
+	}
+	grafanaDefault := &metav1.Duration{Duration: time.Hour}
+
+	if got := dashboard.EffectiveContentCacheDuration(grafanaDefault); got != time.Minute {
+		t.Fatalf("expected dashboard override to win, got %v", got)
+	}
+}
+
+func TestEffectiveContentCacheDurationFallsBackToGrafanaDefault(t *testing.T) {
+	dashboard := &GrafanaDashboard{}
+	grafanaDefault := &metav1.Duration{Duration: time.Hour}
+
+	if got := dashboard.EffectiveContentCacheDuration(grafanaDefault); got != time.Hour {
+		t.Fatalf("expected grafana-wide default, got %v", got)
+	}
+	if got := dashboard.EffectiveContentCacheDuration(nil); got != 0 {
+		t.Fatalf("expected no caching when neither is set, got %v", got)
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const defaultCommitterName = "grafana-operator"
+const defaultCommitterEmail = "grafana-operator@localhost"
+
+// GrafanaBackupReconciler reconciles a GrafanaBackup object
+type GrafanaBackupReconciler struct {
+	Client client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanabackups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanabackups/status,verbs=get;update;patch
+
+func (r *GrafanaBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reconcileLog := log.FromContext(ctx)
+	r.Log = reconcileLog
+
+	backup := &v1beta1.GrafanaBackup{}
+	if err := r.Client.Get(ctx, req.NamespacedName, backup); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: controllers.RequeueDelayError}, err
+	}
+
+	schedule, err := cron.ParseStandard(backup.Spec.Schedule)
+	if err != nil {
+		reconcileLog.Error(err, "invalid backup schedule", "backup", backup.Name, "schedule", backup.Spec.Schedule)
+		return ctrl.Result{RequeueAfter: controllers.RequeueDelayError}, nil
+	}
+
+	now := time.Now()
+	nextRun := schedule.Next(backup.Status.LastBackupTime.Time)
+	if now.Before(nextRun) {
+		return ctrl.Result{RequeueAfter: nextRun.Sub(now)}, nil
+	}
+
+	instances, err := controllers.GetMatchingInstances(ctx, r.Client, backup.Spec.InstanceSelector)
+	if err != nil {
+		reconcileLog.Error(err, "could not find matching instance", "backup", backup.Name)
+		return ctrl.Result{RequeueAfter: controllers.RequeueDelayError}, err
+	}
+
+	lastCommit := backup.Status.LastBackupCommit
+	for _, grafana := range instances.Items {
+		commit, err := r.runBackup(ctx, backup, &grafana)
+		if err != nil {
+			reconcileLog.Error(err, "backup failed", "backup", backup.Name, "grafana", grafana.Name)
+			return ctrl.Result{RequeueAfter: controllers.RequeueDelayError}, err
+		}
+		if commit != "" {
+			lastCommit = commit
+		}
+	}
+
+	backup.Status.LastBackupTime = metav1.Now()
+	backup.Status.LastBackupCommit = lastCommit
+	if err := r.Client.Status().Update(ctx, backup); err != nil {
+		return ctrl.Result{RequeueAfter: controllers.RequeueDelayError}, err
+	}
+
+	return ctrl.Result{RequeueAfter: schedule.Next(now).Sub(now)}, nil
+}
+
+// runBackup snapshots every dashboard in grafana to backup.Spec.Repository, committing and
+// pushing only when the canonicalized output actually differs from what's already there. It
+// returns the pushed commit SHA, or "" if nothing had changed.
+func (r *GrafanaBackupReconciler) runBackup(ctx context.Context, backup *v1beta1.GrafanaBackup, grafana *v1beta1.Grafana) (string, error) {
+	grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, grafana)
+	if err != nil {
+		return "", err
+	}
+
+	folders, err := grafanaClient.Folders()
+	if err != nil {
+		return "", err
+	}
+	folderNames := map[int64]string{0: "general"}
+	for _, folder := range folders {
+		folderNames[folder.ID] = folder.Title
+	}
+
+	dashboards, err := grafanaClient.Dashboards()
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := os.MkdirTemp("", "grafana-backup-*")
+	if err != nil {
+		return "", err
+	}
+	defer cleanupTempDir(dir)
+
+	auth, err := resolveAuth(ctx, r.Client, backup.Namespace, backup.Spec.Repository)
+	if err != nil {
+		return "", err
+	}
+
+	repository, err := cloneOrOpen(ctx, dir, backup.Spec.Repository, auth)
+	if err != nil {
+		return "", err
+	}
+
+	written := 0
+	keep := map[string]bool{}
+	for _, summary := range dashboards {
+		dashboard, err := grafanaClient.DashboardByUID(summary.UID)
+		if err != nil {
+			return "", err
+		}
+
+		raw, err := json.Marshal(dashboard.Model)
+		if err != nil {
+			return "", err
+		}
+
+		canonical, err := CanonicalizeDashboard(raw)
+		if err != nil {
+			return "", err
+		}
+
+		folderName := folderNames[dashboard.Folder]
+		if folderName == "" {
+			folderName = "general"
+		}
+
+		path := filepath.Join(dir, folderName, summary.UID+".json")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(path, canonical, 0o644); err != nil {
+			return "", err
+		}
+		keep[path] = true
+		written++
+	}
+
+	// a dashboard deleted from Grafana since the last backup left its file behind in the
+	// cloned repo above - prune anything this run didn't (re)write so the repo tracks exactly
+	// the current dashboard set, not a superset that grows forever and that Restore would
+	// otherwise resurrect.
+	if err := pruneStaleDashboardFiles(dir, keep); err != nil {
+		return "", err
+	}
+
+	committerName := backup.Spec.Repository.CommitterName
+	if committerName == "" {
+		committerName = defaultCommitterName
+	}
+	committerEmail := backup.Spec.Repository.CommitterEmail
+	if committerEmail == "" {
+		committerEmail = defaultCommitterEmail
+	}
+	message := backup.Spec.Repository.CommitMessage(grafana.Name, written)
+
+	return commitAndPush(ctx, repository, dir, backup.Spec.Repository, auth, committerName, committerEmail, message)
+}
+
+// pruneStaleDashboardFiles removes any *.json file under dir that wasn't (re)written during
+// this backup run, so a dashboard deleted from Grafana is also removed from the backup repo
+// instead of being left behind forever.
+func pruneStaleDashboardFiles(dir string, keep map[string]bool) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".json" || keep[path] {
+			return nil
+		}
+		return os.Remove(path)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1beta1.GrafanaBackup{}).
+		Complete(r)
+}
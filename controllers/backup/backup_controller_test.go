@@ -0,0 +1,80 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func mustWriteFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("unexpected error creating %v: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("unexpected error writing %v: %v", path, err)
+	}
+}
+
+func TestPruneStaleDashboardFilesRemovesOnlyFilesNotKept(t *testing.T) {
+	dir := t.TempDir()
+
+	kept := filepath.Join(dir, "general", "kept.json")
+	stale := filepath.Join(dir, "general", "stale.json")
+	mustWriteFile(t, kept, "{}")
+	mustWriteFile(t, stale, "{}")
+
+	if err := pruneStaleDashboardFiles(dir, map[string]bool{kept: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(kept); err != nil {
+		t.Fatalf("expected kept file to survive, got %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("expected stale file to be removed, stat returned %v", err)
+	}
+}
+
+func TestPruneStaleDashboardFilesIgnoresGitDirAndNonJsonFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	gitFile := filepath.Join(dir, ".git", "HEAD")
+	readme := filepath.Join(dir, "README.md")
+	mustWriteFile(t, gitFile, "ref: refs/heads/main")
+	mustWriteFile(t, readme, "do not touch")
+
+	if err := pruneStaleDashboardFiles(dir, map[string]bool{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(gitFile); err != nil {
+		t.Fatalf("expected .git contents to be left untouched, got %v", err)
+	}
+	if _, err := os.Stat(readme); err != nil {
+		t.Fatalf("expected non-json files to be left untouched, got %v", err)
+	}
+}
+
+func TestPruneStaleDashboardFilesOnEmptyDirIsNoop(t *testing.T) {
+	dir := t.TempDir()
+	if err := pruneStaleDashboardFiles(dir, map[string]bool{}); err != nil {
+		t.Fatalf("unexpected error on empty dir: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// volatileDashboardFields are stripped before a dashboard is written to the backup repository,
+// so a dashboard that hasn't actually changed doesn't produce a commit just because Grafana
+// bumped its version/iteration on import.
+var volatileDashboardFields = []string{"id", "version", "iteration"}
+
+// CanonicalizeDashboard re-marshals a dashboard's JSON with stable (alphabetically sorted) key
+// order and without its volatile fields, so two exports of the same logical dashboard produce
+// byte-identical output.
+func CanonicalizeDashboard(raw []byte) ([]byte, error) {
+	var model map[string]interface{}
+	if err := json.Unmarshal(raw, &model); err != nil {
+		return nil, err
+	}
+
+	for _, field := range volatileDashboardFields {
+		delete(model, field)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(model); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
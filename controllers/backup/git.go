@@ -0,0 +1,151 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// resolveAuth builds the go-git transport.AuthMethod for a GitRepositorySpec from the
+// referenced Secret, chosen by which keys the Secret holds rather than by the url scheme: an
+// ssh private key if present, else a token, else a username/password pair. No known_hosts
+// entry is read from the Secret.
+func resolveAuth(ctx context.Context, k8sClient k8sclient.Client, namespace string, repo v1beta1.GitRepositorySpec) (transport.AuthMethod, error) {
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, k8sclient.ObjectKey{Namespace: namespace, Name: repo.SecretName}, secret); err != nil {
+		return nil, fmt.Errorf("reading git credentials secret %v/%v: %w", namespace, repo.SecretName, err)
+	}
+
+	if key, ok := secret.Data["sshPrivateKey"]; ok {
+		auth, err := ssh.NewPublicKeys("git", key, "")
+		if err != nil {
+			return nil, fmt.Errorf("parsing sshPrivateKey in secret %v/%v: %w", namespace, repo.SecretName, err)
+		}
+		return auth, nil
+	}
+
+	if token, ok := secret.Data["token"]; ok {
+		return &http.BasicAuth{Username: "token", Password: string(token)}, nil
+	}
+
+	username, hasUser := secret.Data["username"]
+	password, hasPassword := secret.Data["password"]
+	if hasUser && hasPassword {
+		return &http.BasicAuth{Username: string(username), Password: string(password)}, nil
+	}
+
+	return nil, fmt.Errorf("secret %v/%v has none of sshPrivateKey, token or username/password", namespace, repo.SecretName)
+}
+
+// cloneOrOpen clones repo.URL at repo.GetRef() into dir, creating the branch if it doesn't
+// exist on the remote yet (e.g. the very first backup to a fresh repository).
+func cloneOrOpen(ctx context.Context, dir string, repo v1beta1.GitRepositorySpec, auth transport.AuthMethod) (*git.Repository, error) {
+	ref := plumbing.NewBranchReferenceName(repo.GetRef())
+
+	repository, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+		URL:           repo.URL,
+		Auth:          auth,
+		ReferenceName: ref,
+		SingleBranch:  true,
+	})
+	if err == nil {
+		return repository, nil
+	}
+
+	if !stderrors.Is(err, transport.ErrEmptyRemoteRepository) && !stderrors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, fmt.Errorf("cloning %v: %w", repo.URL, err)
+	}
+
+	// the branch (or the whole repository) doesn't exist on the remote yet - start a fresh
+	// one locally and let the first push create it
+	repository, err = git.PlainInit(dir, false)
+	if err != nil {
+		return nil, err
+	}
+	_, err = repository.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{repo.URL}})
+	if err != nil {
+		return nil, err
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: ref, Create: true}); err != nil {
+		return nil, err
+	}
+
+	return repository, nil
+}
+
+// commitAndPush stages everything under dir, commits with the given author/message (skipping
+// the push entirely when the worktree is clean, so the repo doesn't churn on unchanged
+// backups) and pushes to repo.GetRef(). It returns the new commit SHA, or "" if nothing changed.
+func commitAndPush(ctx context.Context, repository *git.Repository, dir string, repo v1beta1.GitRepositorySpec, auth transport.AuthMethod, authorName string, authorEmail string, message string) (string, error) {
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	if err := worktree.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+		return "", err
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return "", err
+	}
+	if status.IsClean() {
+		return "", nil
+	}
+
+	commit, err := worktree.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: authorName, Email: authorEmail, When: time.Now()},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	err = repository.PushContext(ctx, &git.PushOptions{
+		RemoteName: "origin",
+		Auth:       auth,
+	})
+	if err != nil {
+		return "", fmt.Errorf("pushing to %v: %w", repo.URL, err)
+	}
+
+	return commit.String(), nil
+}
+
+func cleanupTempDir(dir string) {
+	_ = os.RemoveAll(dir)
+}
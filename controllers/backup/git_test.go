@@ -0,0 +1,146 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestResolveAuthPrefersSshPrivateKey(t *testing.T) {
+	key := generateTestSshPrivateKeyPEM(t)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "git-creds"},
+		Data:       map[string][]byte{"sshPrivateKey": key},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+
+	auth, err := resolveAuth(context.Background(), k8sClient, "default", v1beta1.GitRepositorySpec{SecretName: "git-creds"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := auth.(*ssh.PublicKeys); !ok {
+		t.Fatalf("expected ssh.PublicKeys auth, got %T", auth)
+	}
+}
+
+func TestResolveAuthUsesTokenAsBasicAuth(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "git-creds"},
+		Data:       map[string][]byte{"token": []byte("abc123")},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+
+	auth, err := resolveAuth(context.Background(), k8sClient, "default", v1beta1.GitRepositorySpec{SecretName: "git-creds"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	basic, ok := auth.(*http.BasicAuth)
+	if !ok {
+		t.Fatalf("expected http.BasicAuth, got %T", auth)
+	}
+	if basic.Username != "token" || basic.Password != "abc123" {
+		t.Fatalf("expected username %q password %q, got username %q password %q", "token", "abc123", basic.Username, basic.Password)
+	}
+}
+
+func TestResolveAuthUsesUsernameAndPassword(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "git-creds"},
+		Data:       map[string][]byte{"username": []byte("alice"), "password": []byte("hunter2")},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+
+	auth, err := resolveAuth(context.Background(), k8sClient, "default", v1beta1.GitRepositorySpec{SecretName: "git-creds"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	basic, ok := auth.(*http.BasicAuth)
+	if !ok {
+		t.Fatalf("expected http.BasicAuth, got %T", auth)
+	}
+	if basic.Username != "alice" || basic.Password != "hunter2" {
+		t.Fatalf("expected username %q password %q, got username %q password %q", "alice", "hunter2", basic.Username, basic.Password)
+	}
+}
+
+func TestResolveAuthErrorsWhenSecretHasNoRecognizedKeys(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "git-creds"},
+		Data:       map[string][]byte{"unrelated": []byte("value")},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+
+	if _, err := resolveAuth(context.Background(), k8sClient, "default", v1beta1.GitRepositorySpec{SecretName: "git-creds"}); err == nil {
+		t.Fatalf("expected an error when the secret has none of sshPrivateKey, token or username/password")
+	}
+}
+
+func TestResolveAuthErrorsWhenSecretMissing(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+	if _, err := resolveAuth(context.Background(), k8sClient, "default", v1beta1.GitRepositorySpec{SecretName: "missing"}); err == nil {
+		t.Fatalf("expected an error when the referenced secret doesn't exist")
+	}
+}
+
+func TestCleanupTempDirRemovesDirectory(t *testing.T) {
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "sub", "file.txt")
+	if err := os.MkdirAll(filepath.Dir(nested), 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(nested, []byte("x"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cleanupTempDir(dir)
+
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected dir to be removed, stat returned %v", err)
+	}
+}
+
+// generateTestSshPrivateKeyPEM generates a throwaway PKCS1 RSA private key PEM so
+// resolveAuth's ssh.NewPublicKeys parsing can be exercised without a hardcoded fixture.
+func generateTestSshPrivateKeyPEM(t *testing.T) []byte {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error generating key: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	client2 "github.com/grafana-operator/grafana-operator-experimental/controllers/client"
+	grapi "github.com/grafana/grafana-api-golang-client"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RestoreMode selects how Restore applies the dashboards it reads back from git.
+type RestoreMode string
+
+const (
+	// RestoreModeCreateDashboardCRs restores by creating/updating a GrafanaDashboard CR per
+	// backed-up dashboard, letting GrafanaDashboardReconciler take it from there.
+	RestoreModeCreateDashboardCRs RestoreMode = "dashboardCRs"
+
+	// RestoreModeDirectImport restores by importing straight into the Grafana instance via
+	// the same client path the dashboard reconciler uses, bypassing the CRs entirely.
+	RestoreModeDirectImport RestoreMode = "directImport"
+)
+
+// Restore clones backup.Spec.Repository at its configured ref and replays every <folder>/<uid>.json
+// file it finds, either as GrafanaDashboard CRs in restoreNamespace or as direct imports into
+// grafana, depending on mode. It is invoked on demand by an operator (CLI, short-lived Job), not
+// automatically by GrafanaBackupReconciler.
+func Restore(ctx context.Context, k8sClient k8sclient.Client, backup *v1beta1.GrafanaBackup, grafana *v1beta1.Grafana, restoreNamespace string, mode RestoreMode) (int, error) {
+	dir, err := os.MkdirTemp("", "grafana-restore-*")
+	if err != nil {
+		return 0, err
+	}
+	defer cleanupTempDir(dir)
+
+	auth, err := resolveAuth(ctx, k8sClient, backup.Namespace, backup.Spec.Repository)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := cloneOrOpen(ctx, dir, backup.Spec.Repository, auth); err != nil {
+		return 0, err
+	}
+
+	var grafanaClient *grapi.Client
+	if mode == RestoreModeDirectImport {
+		grafanaClient, err = client2.NewGrafanaClient(ctx, k8sClient, grafana)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	restored := 0
+	err = filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		folder := filepath.Base(filepath.Dir(path))
+		uid := strings.TrimSuffix(filepath.Base(path), ".json")
+
+		switch mode {
+		case RestoreModeDirectImport:
+			err = restoreDirect(grafanaClient, folder, uid, content)
+		default:
+			err = restoreAsDashboardCR(ctx, k8sClient, restoreNamespace, grafana, folder, uid, content)
+		}
+		if err != nil {
+			return err
+		}
+
+		restored++
+		return nil
+	})
+	if err != nil {
+		return restored, err
+	}
+
+	backup.Status.LastRestoreTime = metav1.Now()
+	return restored, k8sClient.Status().Update(ctx, backup)
+}
+
+func restoreDirect(grafanaClient *grapi.Client, folder string, uid string, content []byte) error {
+	var model map[string]interface{}
+	if err := json.Unmarshal(content, &model); err != nil {
+		return err
+	}
+	model["uid"] = uid
+
+	folderID, err := findOrCreateFolder(grafanaClient, folder)
+	if err != nil {
+		return err
+	}
+
+	_, err = grafanaClient.NewDashboard(grapi.Dashboard{
+		Meta: grapi.DashboardMeta{
+			Slug:   uid,
+			Folder: folderID,
+		},
+		Model:     model,
+		Overwrite: true,
+	})
+	return err
+}
+
+func findOrCreateFolder(grafanaClient *grapi.Client, title string) (int64, error) {
+	if title == "general" || title == "" {
+		return 0, nil
+	}
+
+	folders, err := grafanaClient.Folders()
+	if err != nil {
+		return 0, err
+	}
+	for _, folder := range folders {
+		if folder.Title == title {
+			return folder.ID, nil
+		}
+	}
+
+	created, err := grafanaClient.NewFolder(title)
+	if err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+func restoreAsDashboardCR(ctx context.Context, k8sClient k8sclient.Client, namespace string, grafana *v1beta1.Grafana, folder string, uid string, content []byte) error {
+	name := strings.ToLower(uid)
+
+	dashboard := &v1beta1.GrafanaDashboard{}
+	err := k8sClient.Get(ctx, k8sclient.ObjectKey{Namespace: namespace, Name: name}, dashboard)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	dashboard.Name = name
+	dashboard.Namespace = namespace
+	dashboard.Spec.Json = string(content)
+	if folder != "general" {
+		dashboard.Spec.FolderTitle = folder
+	}
+	dashboard.Spec.InstanceSelector = &metav1.LabelSelector{MatchLabels: grafana.Labels}
+
+	if errors.IsNotFound(err) {
+		return k8sClient.Create(ctx, dashboard)
+	}
+	return k8sClient.Update(ctx, dashboard)
+}
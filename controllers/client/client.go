@@ -0,0 +1,118 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	"github.com/grafana-operator/grafana-operator-experimental/controllers/metrics"
+	grapi "github.com/grafana/grafana-api-golang-client"
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultClientTimeout = 10 * time.Second
+
+// NewGrafanaClient builds a grafana-api-golang-client for the given Grafana instance,
+// configured from spec.client: a per-instance CA bundle / client certificate (spec.client.tls),
+// a request timeout (spec.client.timeout, default 10s) and an optional proxy
+// (spec.client.proxyURL).
+func NewGrafanaClient(ctx context.Context, k8sClient k8sclient.Client, grafana *v1beta1.Grafana) (*grapi.Client, error) {
+	if grafana.Status.AdminUrl == "" {
+		return nil, fmt.Errorf("grafana instance %v/%v has no admin url yet", grafana.Namespace, grafana.Name)
+	}
+
+	tlsConfig, err := buildTLSConfig(ctx, k8sClient, grafana)
+	if err != nil {
+		return nil, err
+	}
+
+	var proxyURL *url.URL
+	if grafana.Spec.Client != nil && grafana.Spec.Client.ProxyURL != "" {
+		proxyURL, err = url.Parse(grafana.Spec.Client.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	httpClient := &http.Client{
+		Timeout: clientTimeout(grafana),
+		Transport: NewInstrumentedRoundTripper(
+			grafana.Namespace, grafana.Name,
+			metrics.GrafanaApiRequests, metrics.GrafanaApiRequestDuration, metrics.GrafanaApiTransportErrors,
+			tlsConfig, proxyURL),
+	}
+
+	return grapi.New(grafana.Status.AdminUrl, grapi.Config{Client: httpClient})
+}
+
+func clientTimeout(grafana *v1beta1.Grafana) time.Duration {
+	if grafana.Spec.Client != nil && grafana.Spec.Client.Timeout != nil {
+		return grafana.Spec.Client.Timeout.Duration
+	}
+	return defaultClientTimeout
+}
+
+// buildTLSConfig resolves the *tls.Config to use for this instance: the CA bundle and/or
+// client certificate referenced by spec.client.tls.secretName, if any, plus its
+// insecureSkipVerify opt-out. With no spec.client.tls at all, certificates are verified
+// normally against the system trust store.
+func buildTLSConfig(ctx context.Context, k8sClient k8sclient.Client, grafana *v1beta1.Grafana) (*tls.Config, error) {
+	if grafana.Spec.Client == nil || grafana.Spec.Client.TLS == nil {
+		return &tls.Config{}, nil
+	}
+
+	tlsSpec := grafana.Spec.Client.TLS
+	tlsConfig := &tls.Config{InsecureSkipVerify: tlsSpec.InsecureSkipVerify}
+
+	if tlsSpec.SecretName == "" {
+		return tlsConfig, nil
+	}
+
+	secret := &corev1.Secret{}
+	err := k8sClient.Get(ctx, k8sclient.ObjectKey{Namespace: grafana.Namespace, Name: tlsSpec.SecretName}, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if ca, ok := secret.Data["ca.crt"]; ok {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("failed to parse ca.crt in secret %v/%v", grafana.Namespace, tlsSpec.SecretName)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	crt, hasCrt := secret.Data["tls.crt"]
+	key, hasKey := secret.Data["tls.key"]
+	if hasCrt && hasKey {
+		cert, err := tls.X509KeyPair(crt, key)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
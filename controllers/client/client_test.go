@@ -0,0 +1,196 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClientTimeoutDefaultsWhenUnset(t *testing.T) {
+	grafana := &v1beta1.Grafana{}
+	if got := clientTimeout(grafana); got != defaultClientTimeout {
+		t.Fatalf("expected default timeout %v, got %v", defaultClientTimeout, got)
+	}
+}
+
+func TestClientTimeoutUsesConfiguredOverride(t *testing.T) {
+	grafana := &v1beta1.Grafana{
+		Spec: v1beta1.GrafanaSpec{
+			Client: &v1beta1.GrafanaClientSpec{
+				Timeout: &metav1.Duration{Duration: 30 * time.Second},
+			},
+		},
+	}
+	if got := clientTimeout(grafana); got != 30*time.Second {
+		t.Fatalf("expected configured timeout 30s, got %v", got)
+	}
+}
+
+func TestBuildTLSConfigNoSpecReturnsEmptyConfig(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	grafana := &v1beta1.Grafana{}
+
+	tlsConfig, err := buildTLSConfig(context.Background(), k8sClient, grafana)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tlsConfig.InsecureSkipVerify || tlsConfig.RootCAs != nil || tlsConfig.Certificates != nil {
+		t.Fatalf("expected an empty tls.Config, got %+v", tlsConfig)
+	}
+}
+
+func TestBuildTLSConfigWithoutSecretNameHonorsInsecureSkipVerify(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	grafana := &v1beta1.Grafana{
+		Spec: v1beta1.GrafanaSpec{
+			Client: &v1beta1.GrafanaClientSpec{
+				TLS: &v1beta1.GrafanaClientTLS{InsecureSkipVerify: true},
+			},
+		},
+	}
+
+	tlsConfig, err := buildTLSConfig(context.Background(), k8sClient, grafana)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !tlsConfig.InsecureSkipVerify {
+		t.Fatalf("expected InsecureSkipVerify to carry through")
+	}
+}
+
+func TestBuildTLSConfigMissingSecretReturnsError(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	grafana := &v1beta1.Grafana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+		Spec: v1beta1.GrafanaSpec{
+			Client: &v1beta1.GrafanaClientSpec{
+				TLS: &v1beta1.GrafanaClientTLS{SecretName: "missing-secret"},
+			},
+		},
+	}
+
+	if _, err := buildTLSConfig(context.Background(), k8sClient, grafana); err == nil {
+		t.Fatalf("expected an error when the referenced secret doesn't exist")
+	}
+}
+
+func TestBuildTLSConfigLoadsCABundleAndClientCertFromSecret(t *testing.T) {
+	caCertPEM, certPEM, keyPEM := mustSelfSignedCertAndKey(t)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "grafana-tls"},
+		Data: map[string][]byte{
+			"ca.crt":  caCertPEM,
+			"tls.crt": certPEM,
+			"tls.key": keyPEM,
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+
+	grafana := &v1beta1.Grafana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+		Spec: v1beta1.GrafanaSpec{
+			Client: &v1beta1.GrafanaClientSpec{
+				TLS: &v1beta1.GrafanaClientTLS{SecretName: "grafana-tls"},
+			},
+		},
+	}
+
+	tlsConfig, err := buildTLSConfig(context.Background(), k8sClient, grafana)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tlsConfig.RootCAs == nil {
+		t.Fatalf("expected ca.crt to populate RootCAs")
+	}
+	if len(tlsConfig.Certificates) != 1 {
+		t.Fatalf("expected tls.crt/tls.key to populate a single client certificate, got %v", len(tlsConfig.Certificates))
+	}
+}
+
+func TestBuildTLSConfigRejectsMalformedCABundle(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "grafana-tls"},
+		Data:       map[string][]byte{"ca.crt": []byte("not a certificate")},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+
+	grafana := &v1beta1.Grafana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+		Spec: v1beta1.GrafanaSpec{
+			Client: &v1beta1.GrafanaClientSpec{
+				TLS: &v1beta1.GrafanaClientTLS{SecretName: "grafana-tls"},
+			},
+		},
+	}
+
+	if _, err := buildTLSConfig(context.Background(), k8sClient, grafana); err == nil {
+		t.Fatalf("expected an error for a malformed ca.crt")
+	}
+}
+
+// mustSelfSignedCertAndKey generates a throwaway self-signed certificate/key pair, PEM-encoded,
+// for exercising buildTLSConfig's ca.crt/tls.crt/tls.key parsing without hardcoding fixture PEM.
+func mustSelfSignedCertAndKey(t *testing.T) (caCertPEM, certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error generating key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "grafana-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unexpected error creating certificate: %v", err)
+	}
+
+	certPEMBlock := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error marshaling key: %v", err)
+	}
+	keyPEMBlock := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return certPEMBlock, certPEMBlock, keyPEMBlock
+}
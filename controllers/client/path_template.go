@@ -0,0 +1,84 @@
+package client
+
+import "regexp"
+
+// knownPathSegments are the fixed parts of Grafana's HTTP API routes that normalizePathTemplate
+// must never collapse to :id. Several of these are 8+ letters on their own (dashboards,
+// datasources, organizations, permissions, annotations, snapshots, playlists, preferences), so a
+// length-only heuristic can't tell them apart from an actual identifier.
+var knownPathSegments = map[string]bool{
+	"api": true, "dashboards": true, "dashboard": true, "uid": true, "search": true,
+	"folders": true, "folder": true, "datasources": true, "datasource": true, "proxy": true,
+	"health": true, "admin": true, "users": true, "user": true, "orgs": true, "org": true,
+	"teams": true, "team": true, "members": true, "alerts": true, "alert-notifications": true,
+	"annotations": true, "snapshots": true, "playlists": true, "preferences": true,
+	"permissions": true, "library-elements": true, "access-control": true, "serviceaccounts": true,
+	"ldap": true, "auth": true, "keys": true, "plugins": true, "settings": true, "versions": true,
+	"restore": true, "public-dashboards": true, "by-uid": true, "by-path": true, "by-name": true,
+	"tags": true, "stars": true, "star": true, "frontend": true, "metrics": true, "organizations": true,
+}
+
+// numericSegment matches a purely numeric id, e.g. a dashboard or folder's integer id.
+var numericSegment = regexp.MustCompile(`^[0-9]+$`)
+
+// opaqueIdSegment matches Grafana's actual uid charset (lowercase-friendly alphanumerics plus
+// - and _), 8 or more characters long. normalizePathTemplate additionally requires such a
+// segment to contain at least one digit (hasDigit): real fixed route words are plain English
+// and essentially never contain a digit, while Grafana's generated dashboard/folder uids
+// reliably do, so the two are told apart instead of keying off length alone.
+var opaqueIdSegment = regexp.MustCompile(`^[a-zA-Z0-9_-]{8,}$`)
+
+// normalizePathTemplate replaces identifier-looking segments of a Grafana API path with a
+// placeholder, so e.g. "/api/dashboards/uid/abc123def456" and
+// "/api/dashboards/uid/zzz999yyy888" both report as "/api/dashboards/uid/:id" instead of
+// exploding the path label's cardinality by one value per dashboard - while leaving fixed route
+// segments like "dashboards" or "organizations" alone.
+func normalizePathTemplate(path string) string {
+	segments := splitPath(path)
+	for i, segment := range segments {
+		if knownPathSegments[segment] {
+			continue
+		}
+		if numericSegment.MatchString(segment) || (opaqueIdSegment.MatchString(segment) && hasDigit(segment)) {
+			segments[i] = ":id"
+		}
+	}
+	return joinPath(segments)
+}
+
+func hasDigit(segment string) bool {
+	for _, r := range segment {
+		if r >= '0' && r <= '9' {
+			return true
+		}
+	}
+	return false
+}
+
+func splitPath(path string) []string {
+	var segments []string
+	start := 0
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' {
+			if i > start {
+				segments = append(segments, path[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(path) {
+		segments = append(segments, path[start:])
+	}
+	return segments
+}
+
+func joinPath(segments []string) string {
+	result := ""
+	for _, segment := range segments {
+		result += "/" + segment
+	}
+	if result == "" {
+		return "/"
+	}
+	return result
+}
@@ -0,0 +1,37 @@
+package client
+
+import "testing"
+
+func TestNormalizePathTemplate(t *testing.T) {
+	cases := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"dashboard by uid", "/api/dashboards/uid/abc123def456", "/api/dashboards/uid/:id"},
+		{"two different uids same shape", "/api/dashboards/uid/zzz999yyy888", "/api/dashboards/uid/:id"},
+		{"numeric datasource id", "/api/datasources/1", "/api/datasources/:id"},
+		{"fixed route words are never collapsed", "/api/organizations/preferences", "/api/organizations/preferences"},
+		{"annotations route untouched", "/api/annotations", "/api/annotations"},
+		{"root", "/", "/"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := normalizePathTemplate(c.path); got != c.want {
+				t.Fatalf("normalizePathTemplate(%q) = %q, want %q", c.path, got, c.want)
+			}
+		})
+	}
+}
+
+func TestNormalizePathTemplateDistinguishesRoutesFromUids(t *testing.T) {
+	// two different endpoints must not alias onto the same normalized path just because one
+	// segment happens to be a long fixed word.
+	dashboards := normalizePathTemplate("/api/dashboards/uid/abc12345")
+	datasources := normalizePathTemplate("/api/datasources/uid/abc12345")
+
+	if dashboards == datasources {
+		t.Fatalf("expected distinct endpoints to normalize differently, both got %q", dashboards)
+	}
+}
@@ -4,37 +4,62 @@ import (
 	"crypto/tls"
 	"github.com/prometheus/client_golang/prometheus"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 )
 
 type instrumentedRoundTripper struct {
 	relatedResource string
+	namespace       string
+	name            string
 	wrapped         http.RoundTripper
 	metric          *prometheus.CounterVec
+	duration        *prometheus.HistogramVec
+	transportErrors *prometheus.CounterVec
 }
 
-func NewInstrumentedRoundTripper(relatedResource string, metric *prometheus.CounterVec) http.RoundTripper {
+// NewInstrumentedRoundTripper builds the RoundTripper used for every request to a Grafana
+// instance's API. tlsConfig is per-instance (see GrafanaClientTLS) rather than a blanket
+// skip-verify, and proxyURL, when set, routes requests through an HTTP(S) proxy. namespace and
+// name identify the Grafana instance the request is made on behalf of, for the duration and
+// transport error metrics.
+func NewInstrumentedRoundTripper(namespace string, name string, metric *prometheus.CounterVec, duration *prometheus.HistogramVec, transportErrors *prometheus.CounterVec, tlsConfig *tls.Config, proxyURL *url.URL) http.RoundTripper {
 	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true,
-		},
+		TLSClientConfig: tlsConfig,
+	}
+	if proxyURL != nil {
+		transport.Proxy = http.ProxyURL(proxyURL)
 	}
 
 	return &instrumentedRoundTripper{
-		relatedResource: relatedResource,
+		relatedResource: name,
+		namespace:       namespace,
+		name:            name,
 		wrapped:         transport,
 		metric:          metric,
+		duration:        duration,
+		transportErrors: transportErrors,
 	}
 }
 
 func (in *instrumentedRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
 	resp, err := in.wrapped.RoundTrip(r)
+	elapsed := time.Since(start).Seconds()
+
+	path := normalizePathTemplate(r.URL.Path)
+
 	if resp != nil {
 		in.metric.WithLabelValues(
 			in.relatedResource,
 			r.Method,
 			strconv.Itoa(resp.StatusCode)).
 			Inc()
+		in.duration.WithLabelValues(in.namespace, in.name, path, r.Method).Observe(elapsed)
+	} else if err != nil {
+		in.transportErrors.WithLabelValues(in.namespace, in.name, r.Method).Inc()
 	}
+
 	return resp, err
 }
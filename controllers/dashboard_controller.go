@@ -29,7 +29,9 @@ import (
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/fetchers"
 	"github.com/grafana-operator/grafana-operator-experimental/controllers/metrics"
 	grapi "github.com/grafana/grafana-api-golang-client"
+	"golang.org/x/sync/errgroup"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
 	"net/http"
@@ -42,7 +44,20 @@ import (
 
 const (
 	initialSyncDelay = "10s"
-	syncBatchSize    = 100
+
+	// syncBatchSize caps the number of dashboards deleted from a single Grafana instance per
+	// sync cycle - it's a per-instance budget, not a fleet-wide one, so one instance with a
+	// long backlog no longer starves every other instance's sync.
+	syncBatchSize = 100
+
+	// maxConcurrentInstanceSyncs bounds how many Grafana instances are synced at once, so a
+	// fleet of instances doesn't open an unbounded number of simultaneous Grafana API sessions.
+	maxConcurrentInstanceSyncs = 5
+
+	// baseRetryDelay and maxRetryDelay bound the exponential backoff applied to a dashboard
+	// whose source keeps failing to fetch: baseRetryDelay * 2^retries, capped at maxRetryDelay.
+	baseRetryDelay = 5 * time.Second
+	maxRetryDelay  = 5 * time.Minute
 )
 
 // GrafanaDashboardReconciler reconciles a GrafanaDashboard object
@@ -57,9 +72,16 @@ type GrafanaDashboardReconciler struct {
 //+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadashboards/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadashboards/finalizers,verbs=update
 
+// instanceSyncResult reports the outcome of syncInstanceDashboards for a single Grafana
+// instance, so syncDashboards can aggregate totals and decide whether to requeue without the
+// per-instance goroutines needing to share any mutable state.
+type instanceSyncResult struct {
+	synced     int
+	incomplete bool
+}
+
 func (r *GrafanaDashboardReconciler) syncDashboards(ctx context.Context) (ctrl.Result, error) {
 	syncLog := log.FromContext(ctx)
-	dashboardsSynced := 0
 
 	// get all grafana instances
 	grafanas := &v1beta1.GrafanaList{}
@@ -85,53 +107,101 @@ func (r *GrafanaDashboardReconciler) syncDashboards(ctx context.Context) (ctrl.R
 		}, err
 	}
 
-	// sync dashboards, delete dashboards from grafana that do no longer have a cr
-	dashboardsToDelete := map[*v1beta1.Grafana][]v1beta1.NamespacedResource{}
-	for _, grafana := range grafanas.Items {
-		for _, dashboard := range grafana.Status.Dashboards {
-			if allDashboards.Find(dashboard.Namespace(), dashboard.Name()) == nil {
-				dashboardsToDelete[&grafana] = append(dashboardsToDelete[&grafana], dashboard)
-			}
-		}
+	// sync every instance concurrently, bounded so a large fleet can't open an unbounded number
+	// of simultaneous Grafana API sessions. Each instance owns its own status update, so one
+	// instance's failure doesn't block or roll back another's. Deliberately a plain
+	// errgroup.Group rather than errgroup.WithContext: WithContext cancels a shared derived
+	// context on the first error, which would abort every other in-flight instance's sync (it's
+	// threaded into their own context-aware k8s/Grafana API calls) - the opposite of the point
+	// of syncing instances independently.
+	results := make([]instanceSyncResult, len(grafanas.Items))
+	var group errgroup.Group
+	group.SetLimit(maxConcurrentInstanceSyncs)
+
+	for i := range grafanas.Items {
+		i := i
+		grafana := &grafanas.Items[i]
+		group.Go(func() error {
+			result, err := r.syncInstanceDashboards(ctx, grafana, allDashboards)
+			results[i] = result
+			return err
+		})
 	}
 
-	// delete all dashboards that no longer have a cr
-	for grafana, dashboards := range dashboardsToDelete {
-		grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, grafana)
-		if err != nil {
-			return ctrl.Result{Requeue: true}, err
+	if err := group.Wait(); err != nil {
+		return ctrl.Result{Requeue: false}, err
+	}
+
+	dashboardsSynced, requeue := aggregateSyncResults(results)
+
+	if dashboardsSynced > 0 {
+		syncLog.Info("successfully synced dashboards", "dashboards", dashboardsSynced)
+	}
+	return ctrl.Result{Requeue: requeue}, nil
+}
+
+// aggregateSyncResults totals the per-instance outcomes of syncDashboards' bounded-concurrency
+// fan-out into the dashboard count to log and whether any instance needs a follow-up requeue.
+func aggregateSyncResults(results []instanceSyncResult) (int, bool) {
+	dashboardsSynced := 0
+	requeue := false
+	for _, result := range results {
+		dashboardsSynced += result.synced
+		if result.incomplete {
+			requeue = true
 		}
+	}
+	return dashboardsSynced, requeue
+}
 
+// syncInstanceDashboards deletes the dashboards known to a single Grafana instance that no
+// longer have a matching GrafanaDashboard cr, up to that instance's own syncBatchSize budget.
+func (r *GrafanaDashboardReconciler) syncInstanceDashboards(ctx context.Context, grafana *v1beta1.Grafana, allDashboards *v1beta1.GrafanaDashboardList) (instanceSyncResult, error) {
+	var toDelete []v1beta1.NamespacedResource
+	for namespace, dashboards := range grafana.Status.Dashboards.DashboardsByNamespace() {
 		for _, dashboard := range dashboards {
-			// avoid bombarding the grafana instance with a large number of requests at once, limit
-			// the sync to a certain number of dashboards per cycle. This means that it will take longer to sync
-			// a large number of deleted dashboard crs, but that should be an edge case.
-			if dashboardsSynced >= syncBatchSize {
-				return ctrl.Result{Requeue: true}, nil
+			if allDashboards.Find(namespace, dashboard.Name()) == nil {
+				toDelete = append(toDelete, dashboard)
 			}
+		}
+	}
 
-			namespace, name, uid := dashboard.Split()
-			err = grafanaClient.DeleteDashboardByUID(uid)
-			if err != nil {
-				return ctrl.Result{Requeue: false}, err
-			}
+	if len(toDelete) == 0 {
+		return instanceSyncResult{}, nil
+	}
 
-			grafana.Status.Dashboards = grafana.Status.Dashboards.Remove(namespace, name)
-			dashboardsSynced += 1
+	grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, grafana)
+	if err != nil {
+		return instanceSyncResult{}, err
+	}
+
+	synced := 0
+	incomplete := false
+	for _, dashboard := range toDelete {
+		// avoid bombarding the grafana instance with a large number of requests at once, limit
+		// the sync to a certain number of dashboards per cycle. This means that it will take longer to sync
+		// a large number of deleted dashboard crs, but that should be an edge case.
+		if synced >= syncBatchSize {
+			incomplete = true
+			break
 		}
 
-		// one update per grafana - this will trigger a reconcile of the grafana controller
-		// so we should minimize those updates
-		err = r.Client.Status().Update(ctx, grafana)
-		if err != nil {
-			return ctrl.Result{Requeue: false}, err
+		namespace, name, uid := dashboard.Split()
+		if err := grafanaClient.DeleteDashboardByUID(uid); err != nil {
+			return instanceSyncResult{synced: synced}, err
 		}
+
+		grafana.Status.Dashboards = grafana.Status.Dashboards.Remove(namespace, name)
+		synced += 1
 	}
 
-	if dashboardsSynced > 0 {
-		syncLog.Info("successfully synced dashboards", "dashboards", dashboardsSynced)
+	// one update per grafana - this will trigger a reconcile of the grafana controller
+	// so we should minimize those updates
+	if err := r.Client.Status().Update(ctx, grafana); err != nil {
+		return instanceSyncResult{synced: synced}, err
 	}
-	return ctrl.Result{Requeue: false}, nil
+
+	return instanceSyncResult{synced: synced, incomplete: incomplete}, nil
 }
 
 func (r *GrafanaDashboardReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -185,6 +255,22 @@ func (r *GrafanaDashboardReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 	controllerLog.Info("found matching Grafana instances for dashboard", "count", len(instances.Items))
 
+	// a source that keeps failing to fetch is backed off exponentially and, past MaxRetries,
+	// left alone until the spec changes or RetryCooldown elapses - don't hammer it in between
+	if !dashboard.ShouldRetry() {
+		controllerLog.Info("dashboard source is still failing, waiting for cooldown", "dashboard", dashboard.Name, "retries", dashboard.Status.Error.Retries)
+		return ctrl.Result{RequeueAfter: r.retryDelay(dashboard)}, nil
+	}
+
+	// fetch the dashboard content once per reconcile rather than once per matched instance -
+	// otherwise a dashboard pushed to N instances would make N http calls per reconcile and,
+	// on failure, increment Status.Error.Retries N times, making the backoff/cooldown math
+	// above meaningless once more than one instance is involved.
+	if err := r.fetchAndPersistDashboard(ctx, dashboard, &instances.Items[0]); err != nil {
+		controllerLog.Error(err, "error fetching dashboard source", "dashboard", dashboard.Name)
+		return ctrl.Result{RequeueAfter: r.retryDelay(dashboard)}, nil
+	}
+
 	success := true
 	for _, grafana := range instances.Items {
 		// an admin url is required to interact with grafana
@@ -218,7 +304,26 @@ func (r *GrafanaDashboardReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{RequeueAfter: dashboard.GetResyncPeriod()}, nil
 	}
 
-	return ctrl.Result{RequeueAfter: RequeueDelayError}, nil
+	return ctrl.Result{RequeueAfter: r.retryDelay(dashboard)}, nil
+}
+
+// retryDelay returns how long to wait before the next reconcile of a dashboard that failed to
+// sync. A fetch failure backs off exponentially up to maxRetryDelay; once MaxRetries has been
+// exhausted it instead waits out the dashboard's RetryCooldown. Failures unrelated to fetching
+// the source (e.g. a Grafana instance not yet ready) fall back to the fixed RequeueDelayError.
+func (r *GrafanaDashboardReconciler) retryDelay(dashboard *v1beta1.GrafanaDashboard) time.Duration {
+	if dashboard.Status.Error == nil {
+		return RequeueDelayError
+	}
+	if !dashboard.ShouldRetry() {
+		return dashboard.RetryCooldown()
+	}
+
+	delay := baseRetryDelay * time.Duration(1<<uint(dashboard.Status.Error.Retries))
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
 }
 
 func (r *GrafanaDashboardReconciler) onDashboardDeleted(ctx context.Context, namespace string, name string) error {
@@ -278,16 +383,41 @@ func (r *GrafanaDashboardReconciler) onDashboardDeleted(ctx context.Context, nam
 	return nil
 }
 
-func (r *GrafanaDashboardReconciler) onDashboardCreated(ctx context.Context, grafana *v1beta1.Grafana, cr *v1beta1.GrafanaDashboard) error {
-	dashboardJson, err := r.fetchDashboardJson(cr)
+// fetchAndPersistDashboard fetches the dashboard content and persists the result - the content
+// cache, resolved source and any fetch error - to cr.Status. The caller runs this once per
+// reconcile regardless of how many Grafana instances the dashboard is matched to, so a failing
+// source only ever costs one Status.Error.Retries increment per reconcile instead of one per
+// instance. grafana is only used for its cluster-wide EffectiveContentCacheDuration default;
+// since the fetch is shared across every matched instance, the first one is used for that.
+func (r *GrafanaDashboardReconciler) fetchAndPersistDashboard(ctx context.Context, cr *v1beta1.GrafanaDashboard, grafana *v1beta1.Grafana) error {
+	wasError := cr.Status.Error != nil
+	previousResolvedSource := cr.Status.ResolvedSource
+
+	dashboardJson, resolvedSource, cacheChanged, err := r.fetchDashboardJson(ctx, cr, grafana)
 	if err != nil {
+		r.recordFetchError(ctx, cr, err)
 		return err
 	}
+	cr.Status.Error = nil
+	cr.Status.ResolvedSource = resolvedSource
+
+	// Only persist the status here when the fetch actually changed something - a refreshed
+	// content cache, a cleared error, or a different resolved source - so a dashboard that's
+	// served entirely from an already-fresh cache doesn't keep writing the status subresource
+	// every reconcile.
+	if cacheChanged || wasError || previousResolvedSource != resolvedSource {
+		if err := r.Client.Status().Update(ctx, cr); err != nil {
+			return err
+		}
+	}
 
 	// Dashboards come from different sources, whereas Spec.Json is used to calculate hash
 	// So, we should keep the field updated to make sure changes in dashboards get noticed
 	cr.Spec.Json = string(dashboardJson)
+	return nil
+}
 
+func (r *GrafanaDashboardReconciler) onDashboardCreated(ctx context.Context, grafana *v1beta1.Grafana, cr *v1beta1.GrafanaDashboard) error {
 	grafanaClient, err := client2.NewGrafanaClient(ctx, r.Client, grafana)
 	if err != nil {
 		return err
@@ -303,7 +433,7 @@ func (r *GrafanaDashboardReconciler) onDashboardCreated(ctx context.Context, gra
 	}
 
 	var dashboardFromJson map[string]interface{}
-	err = json.Unmarshal(dashboardJson, &dashboardFromJson)
+	err = json.Unmarshal([]byte(cr.Spec.Json), &dashboardFromJson)
 	if err != nil {
 		return err
 	}
@@ -342,29 +472,82 @@ func (r *GrafanaDashboardReconciler) onDashboardCreated(ctx context.Context, gra
 	return r.UpdateStatus(ctx, cr)
 }
 
-// fetchDashboardJson delegates obtaining the dashboard json definition to one of the known fetchers, for example
-// from embedded raw json or from a url
-func (r *GrafanaDashboardReconciler) fetchDashboardJson(dashboard *v1beta1.GrafanaDashboard) ([]byte, error) {
-	sourceTypes := dashboard.GetSourceTypes()
+// recordFetchError updates cr.Status.Error to reflect a failed fetch attempt, resetting the
+// retry count whenever the dashboard's source has changed since the last failure.
+func (r *GrafanaDashboardReconciler) recordFetchError(ctx context.Context, cr *v1beta1.GrafanaDashboard, fetchErr error) {
+	fingerprint := cr.SpecFingerprint()
 
-	if len(sourceTypes) == 0 {
-		return nil, stderr.New(fmt.Sprintf("no source type provided for dashboard %v", dashboard.Name))
+	errStatus := cr.Status.Error
+	if errStatus == nil || errStatus.SpecFingerprint != fingerprint {
+		errStatus = &v1beta1.GrafanaDashboardErrorStatus{}
 	}
 
-	if len(sourceTypes) > 1 {
-		return nil, stderr.New(fmt.Sprintf("more than one source types found for dashboard %v", dashboard.Name))
+	var statusErr *fetchers.HttpStatusError
+	if stderr.As(fetchErr, &statusErr) {
+		errStatus.Code = statusErr.StatusCode
+	} else {
+		errStatus.Code = 0
 	}
 
-	switch sourceTypes[0] {
-	case v1beta1.DashboardSourceTypeRawJson:
-		return []byte(dashboard.Spec.Json), nil
-	case v1beta1.DashboardSourceTypeUrl:
-		return fetchers.FetchDashboardFromUrl(dashboard)
-	default:
-		return nil, stderr.New(fmt.Sprintf("unknown source type %v found in dashboard %v", sourceTypes[0], dashboard.Name))
+	errStatus.Message = fetchErr.Error()
+	errStatus.Retries++
+	errStatus.LastAttempt = metav1.Now()
+	errStatus.SpecFingerprint = fingerprint
+	cr.Status.Error = errStatus
+
+	if err := r.Client.Status().Update(ctx, cr); err != nil {
+		r.Log.Error(err, "failed to record dashboard fetch error", "dashboard", cr.Name)
 	}
 }
 
+// fetchDashboardJson delegates obtaining the dashboard json definition to one of the known
+// fetchers, for example from embedded raw json or from a url. When more than one source is
+// configured they are tried in GetSourceTypes' priority order, falling back to the next one on
+// error, so e.g. a grafana.com source can be pinned as primary with a configmap as an offline
+// fallback. The source type that ultimately supplied the json is returned alongside it, along
+// with whether the fetch actually touched dashboard.Status.ContentCache - false on a source that
+// doesn't cache at all, or on a url source served entirely from an already-fresh cache.
+func (r *GrafanaDashboardReconciler) fetchDashboardJson(ctx context.Context, dashboard *v1beta1.GrafanaDashboard, grafana *v1beta1.Grafana) ([]byte, v1beta1.DashboardSourceType, bool, error) {
+	sourceTypes := dashboard.GetSourceTypes()
+
+	if len(sourceTypes) == 0 {
+		return nil, "", false, stderr.New(fmt.Sprintf("no source type provided for dashboard %v", dashboard.Name))
+	}
+
+	var lastErr error
+	for _, sourceType := range sourceTypes {
+		var content []byte
+		var cacheChanged bool
+		var err error
+
+		switch sourceType {
+		case v1beta1.DashboardSourceTypeRawJson:
+			content = []byte(dashboard.Spec.Json)
+		case v1beta1.DashboardSourceTypeUrl:
+			cacheDuration := dashboard.EffectiveContentCacheDuration(grafana.Spec.DashboardContentCacheDuration)
+			content, cacheChanged, err = fetchers.FetchDashboardFromUrl(dashboard, cacheDuration)
+		case v1beta1.DashboardSourceTypeGrafanaCom:
+			content, err = fetchers.FetchDashboardFromGrafanaCom(dashboard)
+		case v1beta1.DashboardSourceTypeConfigMap:
+			content, err = fetchers.FetchDashboardFromConfigMap(ctx, r.Client, dashboard)
+		case v1beta1.DashboardSourceTypeJsonnet:
+			content, err = fetchers.FetchDashboardFromJsonnet(dashboard)
+		default:
+			err = stderr.New(fmt.Sprintf("unknown source type %v found in dashboard %v", sourceType, dashboard.Name))
+		}
+
+		if err != nil {
+			r.Log.Info("dashboard source failed, trying next fallback if any", "dashboard", dashboard.Name, "source", sourceType, "error", err.Error())
+			lastErr = err
+			continue
+		}
+
+		return content, sourceType, cacheChanged, nil
+	}
+
+	return nil, "", false, lastErr
+}
+
 func (r *GrafanaDashboardReconciler) UpdateStatus(ctx context.Context, cr *v1beta1.GrafanaDashboard) error {
 	cr.Status.Hash = cr.Hash()
 	return r.Client.Status().Update(ctx, cr)
@@ -0,0 +1,112 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRetryDelayNoErrorFallsBackToRequeueDelayError(t *testing.T) {
+	r := &GrafanaDashboardReconciler{}
+	dashboard := &v1beta1.GrafanaDashboard{}
+
+	if got := r.retryDelay(dashboard); got != RequeueDelayError {
+		t.Fatalf("expected %v, got %v", RequeueDelayError, got)
+	}
+}
+
+func TestRetryDelayBacksOffExponentiallyUpToMax(t *testing.T) {
+	r := &GrafanaDashboardReconciler{}
+	dashboard := &v1beta1.GrafanaDashboard{
+		Spec: v1beta1.GrafanaDashboardSpec{Url: "http://example.com/dashboard.json"},
+	}
+
+	cases := []struct {
+		retries int32
+		want    time.Duration
+	}{
+		{0, 5 * time.Second},
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{6, 5 * time.Minute}, // 5s*2^6 = 320s > maxRetryDelay, capped
+	}
+
+	for _, c := range cases {
+		dashboard.Status.Error = &v1beta1.GrafanaDashboardErrorStatus{
+			Retries:         c.retries,
+			LastAttempt:     metav1.Now(),
+			SpecFingerprint: dashboard.SpecFingerprint(),
+		}
+		if got := r.retryDelay(dashboard); got != c.want {
+			t.Fatalf("retries=%v: expected %v, got %v", c.retries, c.want, got)
+		}
+	}
+}
+
+func TestRetryDelayUsesRetryCooldownOnceMaxRetriesExhausted(t *testing.T) {
+	r := &GrafanaDashboardReconciler{}
+	dashboard := &v1beta1.GrafanaDashboard{
+		Spec: v1beta1.GrafanaDashboardSpec{
+			Url:           "http://example.com/dashboard.json",
+			RetryCooldown: &metav1.Duration{Duration: 45 * time.Minute},
+		},
+	}
+	dashboard.Status.Error = &v1beta1.GrafanaDashboardErrorStatus{
+		Retries:         dashboard.MaxRetries(),
+		LastAttempt:     metav1.Now(),
+		SpecFingerprint: dashboard.SpecFingerprint(),
+	}
+
+	if got := r.retryDelay(dashboard); got != 45*time.Minute {
+		t.Fatalf("expected RetryCooldown of 45m once MaxRetries is exhausted, got %v", got)
+	}
+}
+
+func TestAggregateSyncResultsSumsCountsAndOrsIncomplete(t *testing.T) {
+	results := []instanceSyncResult{
+		{synced: 3, incomplete: false},
+		{synced: 5, incomplete: true},
+		{synced: 0, incomplete: false},
+	}
+
+	synced, requeue := aggregateSyncResults(results)
+	if synced != 8 {
+		t.Fatalf("expected 8 dashboards synced, got %v", synced)
+	}
+	if !requeue {
+		t.Fatalf("expected requeue to be true when any instance reports incomplete")
+	}
+}
+
+func TestAggregateSyncResultsNoRequeueWhenAllComplete(t *testing.T) {
+	results := []instanceSyncResult{
+		{synced: 1, incomplete: false},
+		{synced: 2, incomplete: false},
+	}
+
+	synced, requeue := aggregateSyncResults(results)
+	if synced != 3 {
+		t.Fatalf("expected 3 dashboards synced, got %v", synced)
+	}
+	if requeue {
+		t.Fatalf("expected requeue to be false when every instance completed")
+	}
+}
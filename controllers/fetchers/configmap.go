@@ -0,0 +1,47 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fetchers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FetchDashboardFromConfigMap reads the dashboard JSON from dashboard.Spec.ConfigMapRef, which
+// must live in the dashboard's own namespace.
+func FetchDashboardFromConfigMap(ctx context.Context, k8sClient client.Client, dashboard *v1beta1.GrafanaDashboard) ([]byte, error) {
+	ref := dashboard.Spec.ConfigMapRef
+
+	configMap := &corev1.ConfigMap{}
+	err := k8sClient.Get(ctx, client.ObjectKey{Namespace: dashboard.Namespace, Name: ref.Name}, configMap)
+	if err != nil {
+		return nil, err
+	}
+
+	if content, ok := configMap.Data[ref.Key]; ok {
+		return []byte(content), nil
+	}
+	if content, ok := configMap.BinaryData[ref.Key]; ok {
+		return content, nil
+	}
+
+	return nil, fmt.Errorf("key %v not found in configmap %v/%v", ref.Key, dashboard.Namespace, ref.Name)
+}
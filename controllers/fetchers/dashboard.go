@@ -0,0 +1,132 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fetchers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HttpStatusError is returned when a dashboard source responds with anything other than 200
+// or 304, so callers can inspect the status code without parsing an error string.
+type HttpStatusError struct {
+	StatusCode int
+	Url        string
+}
+
+func (e *HttpStatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %v fetching dashboard from %v", e.StatusCode, e.Url)
+}
+
+// FetchDashboardFromUrl fetches the dashboard JSON from dashboard.Spec.Url, honouring the
+// content cache recorded in dashboard.Status.ContentCache: a fresh-enough cache for the same
+// url is returned without contacting the source at all, and a stale one is revalidated with a
+// conditional GET so a 304 only costs a round trip, not a full download. The returned changed
+// bool reports whether dashboard.Status.ContentCache was actually touched (a 304 refreshing its
+// FetchedAt, or a full 200 replacing it) - it is false on a cache hit that made no HTTP call at
+// all, so the caller knows when persisting the CR status is actually worthwhile.
+func FetchDashboardFromUrl(dashboard *v1beta1.GrafanaDashboard, cacheDuration time.Duration) (content []byte, changed bool, err error) {
+	url := dashboard.Spec.Url
+	cache := dashboard.Status.ContentCache
+	if cache != nil && cache.SourceUrl != url {
+		// the source changed since the last fetch, the old cache no longer applies
+		cache = nil
+	}
+
+	if cache != nil && cacheDuration > 0 && time.Since(cache.FetchedAt.Time) < cacheDuration {
+		content, err = gunzip(cache.ContentGzip)
+		return content, false, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if cache != nil {
+		if cache.ETag != "" {
+			req.Header.Set("If-None-Match", cache.ETag)
+		}
+		if cache.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cache.LastModified)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cache != nil {
+		cache.FetchedAt = metav1.Now()
+		dashboard.Status.ContentCache = cache
+		content, err = gunzip(cache.ContentGzip)
+		return content, true, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, &HttpStatusError{StatusCode: resp.StatusCode, Url: url}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	compressed, err := gzipBytes(body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	dashboard.Status.ContentCache = &v1beta1.DashboardContentCacheStatus{
+		SourceUrl:    url,
+		FetchedAt:    metav1.Now(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentGzip:  compressed,
+	}
+
+	return body, true, nil
+}
+
+func gzipBytes(content []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(content); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzip(content []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
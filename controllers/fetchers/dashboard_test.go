@@ -0,0 +1,141 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fetchers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+func TestFetchDashboardFromUrlFreshFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", "\"v1\"")
+		w.Write([]byte(`{"title":"test"}`))
+	}))
+	defer server.Close()
+
+	dashboard := &v1beta1.GrafanaDashboard{Spec: v1beta1.GrafanaDashboardSpec{Url: server.URL}}
+
+	content, changed, err := FetchDashboardFromUrl(dashboard, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !changed {
+		t.Fatalf("expected a fresh fetch to report changed=true")
+	}
+	if string(content) != `{"title":"test"}` {
+		t.Fatalf("unexpected content: %s", content)
+	}
+	if dashboard.Status.ContentCache == nil || dashboard.Status.ContentCache.ETag != `"v1"` {
+		t.Fatalf("expected the content cache to be populated with the response ETag")
+	}
+}
+
+func TestFetchDashboardFromUrlCacheHitMakesNoRequest(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.Write([]byte(`{"title":"should not be fetched"}`))
+	}))
+	defer server.Close()
+
+	dashboard := &v1beta1.GrafanaDashboard{
+		Spec: v1beta1.GrafanaDashboardSpec{Url: server.URL},
+		Status: v1beta1.GrafanaDashboardStatus{
+			ContentCache: &v1beta1.DashboardContentCacheStatus{
+				SourceUrl:   server.URL,
+				FetchedAt:   metav1NowMinus(time.Second),
+				ContentGzip: mustGzip(t, []byte(`{"title":"cached"}`)),
+			},
+		},
+	}
+
+	content, changed, err := FetchDashboardFromUrl(dashboard, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if called {
+		t.Fatalf("expected a fresh-enough cache to avoid making an http request at all")
+	}
+	if changed {
+		t.Fatalf("expected a pure cache hit to report changed=false")
+	}
+	if string(content) != `{"title":"cached"}` {
+		t.Fatalf("unexpected content: %s", content)
+	}
+}
+
+func TestFetchDashboardFromUrlNotModifiedRefreshesCacheTimestamp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	cachedContent := mustGzip(t, []byte(`{"title":"cached"}`))
+	dashboard := &v1beta1.GrafanaDashboard{
+		Spec: v1beta1.GrafanaDashboardSpec{Url: server.URL},
+		Status: v1beta1.GrafanaDashboardStatus{
+			ContentCache: &v1beta1.DashboardContentCacheStatus{
+				SourceUrl:   server.URL,
+				FetchedAt:   metav1NowMinus(time.Hour),
+				ETag:        `"v1"`,
+				ContentGzip: cachedContent,
+			},
+		},
+	}
+
+	// stale cache forces revalidation since cacheDuration < time since FetchedAt
+	content, changed, err := FetchDashboardFromUrl(dashboard, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !changed {
+		t.Fatalf("expected a 304 revalidation to report changed=true (it refreshes FetchedAt)")
+	}
+	if string(content) != `{"title":"cached"}` {
+		t.Fatalf("unexpected content: %s", content)
+	}
+}
+
+func TestFetchDashboardFromUrlErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	dashboard := &v1beta1.GrafanaDashboard{Spec: v1beta1.GrafanaDashboardSpec{Url: server.URL}}
+
+	_, changed, err := FetchDashboardFromUrl(dashboard, time.Hour)
+	if err == nil {
+		t.Fatalf("expected an error for a non-200/304 response")
+	}
+	if changed {
+		t.Fatalf("expected changed=false on error")
+	}
+
+	var statusErr *HttpStatusError
+	if !asHttpStatusError(err, &statusErr) {
+		t.Fatalf("expected an HttpStatusError, got %T: %v", err, err)
+	}
+	if statusErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %v", statusErr.StatusCode)
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fetchers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func metav1NowMinus(d time.Duration) metav1.Time {
+	return metav1.Time{Time: time.Now().Add(-d)}
+}
+
+func mustGzip(t *testing.T, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(content); err != nil {
+		t.Fatalf("unexpected error gzipping fixture content: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unexpected error closing gzip writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func asHttpStatusError(err error, target **HttpStatusError) bool {
+	return errors.As(err, target)
+}
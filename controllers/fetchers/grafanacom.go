@@ -0,0 +1,79 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fetchers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+const grafanaComApiBaseUrl = "https://grafana.com/api/dashboards"
+
+// FetchDashboardFromGrafanaCom downloads the dashboard JSON published under
+// dashboard.Spec.GrafanaCom.Id on grafana.com, pinned to GrafanaCom.Revision if set, or the
+// latest published revision otherwise.
+func FetchDashboardFromGrafanaCom(dashboard *v1beta1.GrafanaDashboard) ([]byte, error) {
+	source := dashboard.Spec.GrafanaCom
+
+	revision := source.Revision
+	if revision == 0 {
+		latest, err := latestGrafanaComRevision(source.Id)
+		if err != nil {
+			return nil, err
+		}
+		revision = latest
+	}
+
+	url := fmt.Sprintf("%v/%v/revisions/%v/download", grafanaComApiBaseUrl, source.Id, revision)
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HttpStatusError{StatusCode: resp.StatusCode, Url: url}
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func latestGrafanaComRevision(id int) (int, error) {
+	url := fmt.Sprintf("%v/%v", grafanaComApiBaseUrl, id)
+	resp, err := http.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, &HttpStatusError{StatusCode: resp.StatusCode, Url: url}
+	}
+
+	var meta struct {
+		Revision int `json:"revision"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return 0, err
+	}
+
+	return meta.Revision, nil
+}
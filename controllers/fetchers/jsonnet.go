@@ -0,0 +1,34 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fetchers
+
+import (
+	"github.com/google/go-jsonnet"
+	"github.com/grafana-operator/grafana-operator-experimental/api/v1beta1"
+)
+
+// FetchDashboardFromJsonnet evaluates dashboard.Spec.Jsonnet and returns the resulting JSON.
+func FetchDashboardFromJsonnet(dashboard *v1beta1.GrafanaDashboard) ([]byte, error) {
+	vm := jsonnet.MakeVM()
+
+	content, err := vm.EvaluateAnonymousSnippet(dashboard.Name, dashboard.Spec.Jsonnet)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(content), nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// InitialDashboardSyncDuration tracks how long the periodic dashboard sync reconcile took, in milliseconds.
+	InitialDashboardSyncDuration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "grafana_operator_initial_dashboard_sync_duration",
+		Help: "duration of the periodic dashboard sync reconcile, in milliseconds",
+	})
+
+	// GrafanaApiRequests counts outbound requests made against the Grafana HTTP API, by instance and status.
+	GrafanaApiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "grafana_operator_grafana_api_requests_total",
+		Help: "number of requests sent to the Grafana HTTP API",
+	}, []string{"resource", "method", "status"})
+
+	// GrafanaApiRequestDuration tracks how long requests to the Grafana HTTP API take, per
+	// instance and normalized path template (so e.g. a dashboard uid doesn't explode
+	// cardinality). Buckets are tuned for typical Grafana API calls, 10ms-10s.
+	GrafanaApiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grafana_operator_grafana_api_request_duration_seconds",
+		Help:    "duration of requests sent to the Grafana HTTP API, in seconds",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 11), // 10ms .. ~10.24s
+	}, []string{"namespace", "name", "path", "method"})
+
+	// GrafanaApiTransportErrors counts requests that never got a response at all (DNS, TLS,
+	// connection refused, timeout, ...), which GrafanaApiRequests can't see since it only
+	// observes a status code.
+	GrafanaApiTransportErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "grafana_operator_grafana_api_transport_errors_total",
+		Help: "number of requests to the Grafana HTTP API that failed below the HTTP layer",
+	}, []string{"namespace", "name", "method"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		InitialDashboardSyncDuration,
+		GrafanaApiRequests,
+		GrafanaApiRequestDuration,
+		GrafanaApiTransportErrors,
+	)
+}